@@ -7,11 +7,19 @@ import (
 )
 
 // A Bound represents an enclosed "box" in the 2D Euclidean or Cartesian plane.
-// It does not know anything about the anti-meridian.
+// By default it does not know anything about the anti-meridian, ie. it assumes
+// west <= east. Bounds built with NewGeoBound or NewGeoBoundFromTopLeftBottomRight
+// are antimeridian-aware: if west > east the bound is interpreted as wrapping
+// around the dateline.
 type Bound struct {
 	sw, ne *Point
+	wraps  bool
 }
 
+// geohashAlphabet is the base32 variant used by the standard geohash
+// algorithm, shared by the decoder below and the encoder in boundindex.go.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
 // NewBound creates a new bound given the paramters.
 func NewBound(west, east, south, north float64) *Bound {
 	return &Bound{
@@ -20,6 +28,27 @@ func NewBound(west, east, south, north float64) *Bound {
 	}
 }
 
+// NewGeoBound creates a new bound for Lng/Lat data, taking west and east as
+// given rather than normalizing them. If west > east the bound is assumed to
+// cross the antimeridian, e.g. NewGeoBound(170, -170, -10, 10) describes a
+// box that wraps around the dateline rather than one spanning most of the globe.
+func NewGeoBound(west, east, south, north float64) *Bound {
+	return &Bound{
+		sw:    &Point{west, math.Min(north, south)},
+		ne:    &Point{east, math.Max(north, south)},
+		wraps: west > east,
+	}
+}
+
+// NewGeoBoundFromTopLeftBottomRight creates a wrap-aware bound from its
+// top-left (nw) and bottom-right (se) corners. Unlike NewBoundFromPoints,
+// the corners are not interchangeable: this lets a caller express a bound
+// that wraps the antimeridian unambiguously, e.g. a tile viewport over the
+// Pacific whose top-left is at 170°E and bottom-right is at 170°W.
+func NewGeoBoundFromTopLeftBottomRight(topLeft, bottomRight *Point) *Bound {
+	return NewGeoBound(topLeft.Lng(), bottomRight.Lng(), bottomRight.Lat(), topLeft.Lat())
+}
+
 // NewBoundFromPoints creates a new bound given two opposite corners.
 // These corners can be either sw/ne or se/nw.
 func NewBoundFromPoints(corner, oppositeCorner *Point) *Bound {
@@ -57,6 +86,12 @@ func NewBoundFromMapTile(x, y, z uint64) *Bound {
 	lng1, lat1 := scalarMercatorInverse(x<<shift, y<<shift, 31)
 	lng2, lat2 := scalarMercatorInverse((x+1)<<shift, (y+1)<<shift, 31)
 
+	// at low zoom levels a tile can, in principle, straddle the antimeridian,
+	// in which case lng2 will have wrapped back around to be less than lng1.
+	if lng2 < lng1 {
+		return NewGeoBound(lng1, lng2, math.Min(lat1, lat2), math.Max(lat1, lat2))
+	}
+
 	return &Bound{
 		sw: &Point{math.Min(lng1, lng2), math.Min(lat1, lat2)},
 		ne: &Point{math.Max(lng1, lng2), math.Max(lat1, lat2)},
@@ -83,7 +118,7 @@ func geoHash2ranges(hash string) (float64, float64, float64, float64) {
 
 	for _, r := range hash {
 		// TODO: index step could probably be done better
-		i := strings.Index("0123456789bcdefghjkmnpqrstuvwxyz", string(r))
+		i := strings.Index(geohashAlphabet, string(r))
 		for j := 0x10; j != 0; j >>= 1 {
 			if even {
 				mid := (lngMin + lngMax) / 2.0
@@ -174,8 +209,18 @@ func (b *Bound) Extend(point *Point) *Bound {
 		return b
 	}
 
-	b.sw.SetX(math.Min(b.sw.X(), point.X()))
-	b.ne.SetX(math.Max(b.ne.X(), point.X()))
+	if !b.wraps {
+		b.sw.SetX(math.Min(b.sw.X(), point.X()))
+		b.ne.SetX(math.Max(b.ne.X(), point.X()))
+	} else {
+		// extend whichever edge is closer, along the dateline, to the new point
+		if lngDistance(point.X(), b.sw.X()) < lngDistance(point.X(), b.ne.X()) {
+			b.sw.SetX(point.X())
+		} else {
+			b.ne.SetX(point.X())
+		}
+		b.wraps = b.sw.X() > b.ne.X()
+	}
 
 	b.sw.SetY(math.Min(b.sw.Y(), point.Y()))
 	b.ne.SetY(math.Max(b.ne.Y(), point.Y()))
@@ -183,16 +228,62 @@ func (b *Bound) Extend(point *Point) *Bound {
 	return b
 }
 
-// Union extends this bounds to contain the union of this and the given bounds.
+// Union extends this bound to contain the union of this and the given
+// bound. If neither bound wraps, the result is the plain Cartesian envelope,
+// as with Extend. If either one wraps, the result wraps around the
+// antimeridian if that's the smaller way to cover both. Merging
+// corner-by-corner through Extend, as this used to, can't do that: a lone
+// point carries no wraps information, so folding in a bound that itself
+// wraps silently discarded it and produced the Cartesian envelope the long
+// way around instead.
 func (b *Bound) Union(other *Bound) *Bound {
-	b.Extend(other.SouthWest())
-	b.Extend(other.NorthWest())
-	b.Extend(other.SouthEast())
-	b.Extend(other.NorthEast())
+	if !b.wraps && !other.wraps {
+		b.sw.SetX(math.Min(b.sw.X(), other.sw.X()))
+		b.ne.SetX(math.Max(b.ne.X(), other.ne.X()))
+	} else {
+		west, east := unionLngSpan(b.sw.X(), b.ne.X(), other.sw.X(), other.ne.X())
+		b.sw.SetX(west)
+		b.ne.SetX(east)
+		b.wraps = west > east
+	}
+
+	b.sw.SetY(math.Min(b.sw.Y(), other.sw.Y()))
+	b.ne.SetY(math.Max(b.ne.Y(), other.ne.Y()))
 
 	return b
 }
 
+// unionLngSpan returns the smallest longitude span -- west and east,
+// wrapping the antimeridian if west > east -- that contains both of the
+// given west/east spans. Its boundary is always one of the four input
+// endpoints, so it's enough to try every (west, east) combination of them
+// and keep the narrowest one that actually contains all four.
+func unionLngSpan(w1, e1, w2, e2 float64) (west, east float64) {
+	bestWidth := math.Inf(1)
+	west, east = w1, e1
+
+	for _, cw := range [2]float64{w1, w2} {
+		for _, ce := range [2]float64{e1, e2} {
+			if !lngInInterval(w1, cw, ce) || !lngInInterval(e1, cw, ce) ||
+				!lngInInterval(w2, cw, ce) || !lngInInterval(e2, cw, ce) {
+				continue
+			}
+
+			width := ce - cw
+			if width < 0 {
+				width += 360
+			}
+
+			if width < bestWidth {
+				bestWidth = width
+				west, east = cw, ce
+			}
+		}
+	}
+
+	return west, east
+}
+
 // Contains determines if the point is within the bound.
 // Points on the boundary are considered within.
 func (b *Bound) Contains(point *Point) bool {
@@ -201,6 +292,10 @@ func (b *Bound) Contains(point *Point) bool {
 		return false
 	}
 
+	if b.wraps {
+		return point.X() >= b.sw.X() || point.X() <= b.ne.X()
+	}
+
 	if point.X() < b.sw.X() || b.ne.X() < point.X() {
 		return false
 	}
@@ -208,17 +303,24 @@ func (b *Bound) Contains(point *Point) bool {
 	return true
 }
 
-// Intersects determines if two bounds intersect.
-// Returns true if they are touching.
+// Intersects determines if two bounds intersect. Returns true if they are
+// touching. This is a proper separating-axis test, not a corner-containment
+// check, so it's correct for the "one bound pierces the other without either
+// containing a corner" case (e.g. a tall narrow bound crossing a wide short
+// one). Each bound is split into its non-wrapping longitude parts first, so
+// antimeridian-wrapping bounds are handled the same way as Split() handles
+// them elsewhere in this file.
 func (b *Bound) Intersects(bound *Bound) bool {
-	if bound.Contains(b.sw) || bound.Contains(b.ne) ||
-		bound.Contains(b.SouthEast()) || bound.Contains(b.NorthWest()) {
-		return true
+	if b.sw.Y() > bound.ne.Y() || bound.sw.Y() > b.ne.Y() {
+		return false
 	}
 
-	// now check the completely inside case, only one condition required
-	if b.Contains(bound.sw) {
-		return true
+	for _, part := range b.Split() {
+		for _, other := range bound.Split() {
+			if part.sw.X() <= other.ne.X() && other.sw.X() <= part.ne.X() {
+				return true
+			}
+		}
 	}
 
 	return false
@@ -227,12 +329,33 @@ func (b *Bound) Intersects(bound *Bound) bool {
 // Center returns the center of the bound.
 func (b *Bound) Center() *Point {
 	p := &Point{}
-	p.SetX((b.ne.X() + b.sw.X()) / 2.0)
+
+	if b.wraps {
+		lng := b.sw.X() + b.Width()/2.0
+		if lng > 180 {
+			lng -= 360
+		}
+		p.SetX(lng)
+	} else {
+		p.SetX((b.ne.X() + b.sw.X()) / 2.0)
+	}
+
 	p.SetY((b.ne.Y() + b.sw.Y()) / 2.0)
 
 	return p
 }
 
+// lngDistance returns the shortest angular distance, in degrees, between
+// two longitudes, taking the antimeridian wrap into account.
+func lngDistance(a, b float64) float64 {
+	d := math.Abs(a - b)
+	if d > 180 {
+		d = 360 - d
+	}
+
+	return d
+}
+
 // Pad expands the bound in all directions by the amount given. The amount must be
 // in the units of the bounds. Technically one can pad with negative value,
 // but no error checking is done.
@@ -267,7 +390,13 @@ func (b *Bound) Height() float64 {
 }
 
 // Width returns just the difference in the point's X/Longitude.
+// For a bound that wraps the antimeridian this is the span going
+// through the dateline, not the Cartesian ne.X() - sw.X().
 func (b *Bound) Width() float64 {
+	if b.wraps {
+		return 360 - b.sw.X() + b.ne.X()
+	}
+
 	return b.ne.X() - b.sw.X()
 }
 
@@ -288,6 +417,126 @@ func (b *Bound) GeoWidth(haversine ...bool) float64 {
 	return A.GeoDistanceFrom(B, yesHaversine(haversine))
 }
 
+// NewGeoBoundFromPath computes the tight spherical bounding box of the
+// geodesic edges connecting consecutive points of the path. Unlike
+// NewBoundFromPoints, this accounts for the fact that a great-circle edge
+// can bulge north or south of its endpoints' latitudes, and can cross the
+// antimeridian even when neither endpoint is near it.
+func NewGeoBoundFromPath(path *Path) *Bound {
+	points := path.Points()
+	if len(points) == 0 {
+		return NewBound(0, 0, 0, 0)
+	}
+	if len(points) == 1 {
+		return NewBoundFromPoints(points[0], points[0])
+	}
+
+	// seed from the first edge's own geodesic bound, so the result carries
+	// that edge's wraps flag from the start; each later edge is folded in
+	// with Union, which keeps a wrap when either side has one.
+	b := geoEdgeBound(points[0], points[1])
+	for i := 1; i < len(points)-1; i++ {
+		b.GeoExtendEdge(points[i], points[i+1])
+	}
+
+	return b
+}
+
+// GeoExtendEdge grows the bound to include the tight spherical bounding box
+// of the great-circle edge connecting a and c, not just the two endpoints.
+// Both points must be valid Lng/Lat coordinates.
+func (b *Bound) GeoExtendEdge(a, c *Point) *Bound {
+	return b.Union(geoEdgeBound(a, c))
+}
+
+// geoEdgeBound returns the tight wrap-aware bounding box of the great-circle
+// edge between a and c.
+func geoEdgeBound(a, c *Point) *Bound {
+	west, east := a.Lng(), c.Lng()
+	if math.Abs(west-east) > 180 {
+		// the shorter arc crosses the dateline
+		west, east = math.Max(a.Lng(), c.Lng()), math.Min(a.Lng(), c.Lng())
+	} else if west > east {
+		west, east = east, west
+	}
+
+	south, north := math.Min(a.Lat(), c.Lat()), math.Max(a.Lat(), c.Lat())
+
+	ax, ay, az := geoUnitVector(a)
+	cx, cy, cz := geoUnitVector(c)
+
+	// n is the pole of the great circle plane through a and c.
+	nx := ay*cz - az*cy
+	ny := az*cx - ax*cz
+	nz := ax*cy - ay*cx
+
+	norm := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if norm < 1e-12 {
+		// a and c are coincident or antipodal: the edge doesn't define
+		// a unique great circle, so fall back to the endpoint bound.
+		return NewGeoBound(west, east, south, north)
+	}
+
+	// The two points of maximum |latitude| on the great circle are where
+	// its plane comes closest to the poles, i.e. the unit vector closest to
+	// (0, 0, ±1) while still satisfying v . n = 0. That's the component of
+	// (0, 0, 1) orthogonal to n (and its antipode), normalized.
+	zDotN := nz / norm
+	px := -zDotN * nx / norm
+	py := -zDotN * ny / norm
+	pz := 1 - zDotN*nz/norm
+
+	pnorm := math.Sqrt(px*px + py*py + pz*pz)
+	if pnorm > 1e-12 {
+		vertexLng := rad2deg(math.Atan2(py, px))
+		vertexLat := rad2deg(math.Asin(pz / pnorm))
+
+		candidates := [2]struct {
+			lng, lat float64
+		}{
+			{normalizeLng(vertexLng), vertexLat},
+			{normalizeLng(vertexLng + 180), -vertexLat},
+		}
+
+		for _, p := range candidates {
+			if lngInInterval(p.lng, west, east) {
+				south = math.Min(south, p.lat)
+				north = math.Max(north, p.lat)
+			}
+		}
+	}
+
+	return NewGeoBound(west, east, south, north)
+}
+
+// geoUnitVector converts a Lng/Lat point into its unit vector representation.
+func geoUnitVector(p *Point) (x, y, z float64) {
+	lng, lat := deg2rad(p.Lng()), deg2rad(p.Lat())
+	return math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)
+}
+
+// normalizeLng wraps a longitude into the range (-180, 180].
+func normalizeLng(lng float64) float64 {
+	for lng <= -180 {
+		lng += 360
+	}
+	for lng > 180 {
+		lng -= 360
+	}
+
+	return lng
+}
+
+// lngInInterval reports whether lng falls within [west, east], taking the
+// antimeridian wrap into account when west > east.
+func lngInInterval(lng, west, east float64) bool {
+	if west <= east {
+		return lng >= west && lng <= east
+	}
+
+	return lng >= west || lng <= east
+}
+
 // SouthWest returns the lower left corner of the bound.
 func (b *Bound) SouthWest() *Point { return b.sw.Clone() }
 
@@ -310,14 +559,19 @@ func (b *Bound) NorthWest() *Point {
 
 // Empty returns true if it contains zero area or if
 // it's in some malformed negative state where the left point is larger than the right.
-// This can be caused by Padding too much negative.
+// This can be caused by Padding too much negative. A wrapping bound is never
+// considered malformed on its longitude span, since sw.X() > ne.X() is expected.
 func (b *Bound) Empty() bool {
+	if b.wraps {
+		return b.sw.Y() >= b.ne.Y()
+	}
+
 	return b.sw.X() >= b.ne.X() || b.sw.Y() >= b.ne.Y()
 }
 
 // Equals returns if two bounds are equal.
 func (b *Bound) Equals(c *Bound) bool {
-	if b.sw.Equals(c.sw) && b.ne.Equals(c.ne) {
+	if b.wraps == c.wraps && b.sw.Equals(c.sw) && b.ne.Equals(c.ne) {
 		return true
 	}
 
@@ -326,9 +580,29 @@ func (b *Bound) Equals(c *Bound) bool {
 
 // Clone returns a copy of the bound.
 func (b *Bound) Clone() *Bound {
+	if b.wraps {
+		return NewGeoBound(b.sw.X(), b.ne.X(), b.sw.Y(), b.ne.Y())
+	}
+
 	return NewBoundFromPoints(b.sw, b.ne)
 }
 
+// Split breaks a bound that crosses the antimeridian into two non-wrapping
+// Cartesian bounds, one on either side of the dateline. For a bound that
+// doesn't wrap, it returns a single-element slice containing a clone of
+// itself. This is useful for feeding the bound into systems, such as MySQL's
+// spatial INTERSECTS, that only understand ordinary west <= east boxes.
+func (b *Bound) Split() []*Bound {
+	if !b.wraps {
+		return []*Bound{b.Clone()}
+	}
+
+	return []*Bound{
+		NewBound(b.sw.X(), 180, b.sw.Y(), b.ne.Y()),
+		NewBound(-180, b.ne.X(), b.sw.Y(), b.ne.Y()),
+	}
+}
+
 // String returns the string respentation of the bound in the form,
 // [[west, east], [south, north]]
 func (b *Bound) String() string {
@@ -336,7 +610,19 @@ func (b *Bound) String() string {
 }
 
 // ToMysqlPolygon converts the bound into a polygon to be used in a MySQL spacial query.
+// If the bound wraps the antimeridian it is split in two and emitted as a
+// GEOMETRYCOLLECTION, since MySQL's POLYGON can't represent a ring that
+// crosses the dateline.
 func (b *Bound) ToMysqlPolygon() string {
+	if !b.wraps {
+		return b.singleMysqlPolygon()
+	}
+
+	parts := b.Split()
+	return fmt.Sprintf("GEOMETRYCOLLECTION(%s, %s)", parts[0].singleMysqlPolygon(), parts[1].singleMysqlPolygon())
+}
+
+func (b *Bound) singleMysqlPolygon() string {
 	// west, south, west, north, east, north, east, south, west, south
 	return fmt.Sprintf("POLYGON((%f %f, %f %f, %f %f, %f %f, %f %f))", b.sw[0], b.sw[1], b.sw[0], b.ne[1], b.ne[0], b.ne[1], b.ne[0], b.sw[1], b.sw[0], b.sw[1])
 }
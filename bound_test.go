@@ -0,0 +1,123 @@
+package geo
+
+import "testing"
+
+func TestIntersectsPiercing(t *testing.T) {
+	// a tall narrow bound crossing a wide short one: neither contains a
+	// corner of the other, but they clearly overlap.
+	tall := NewBound(-1, 1, -50, 50)
+	wide := NewBound(-50, 50, -1, 1)
+
+	if !tall.Intersects(wide) {
+		t.Error("expected piercing bounds to intersect")
+	}
+	if !wide.Intersects(tall) {
+		t.Error("Intersects should be symmetric")
+	}
+}
+
+func TestIntersectsDisjoint(t *testing.T) {
+	a := NewBound(-10, -5, -10, -5)
+	b := NewBound(5, 10, 5, 10)
+
+	if a.Intersects(b) {
+		t.Error("expected disjoint bounds not to intersect")
+	}
+}
+
+func TestIntersectsTouching(t *testing.T) {
+	a := NewBound(0, 10, 0, 10)
+	b := NewBound(10, 20, 0, 10)
+
+	if !a.Intersects(b) {
+		t.Error("expected bounds sharing an edge to intersect")
+	}
+}
+
+func TestIntersectsContainment(t *testing.T) {
+	outer := NewBound(-10, 10, -10, 10)
+	inner := NewBound(-1, 1, -1, 1)
+
+	if !outer.Intersects(inner) || !inner.Intersects(outer) {
+		t.Error("expected a fully contained bound to intersect its container")
+	}
+}
+
+func TestIntersectsWrap(t *testing.T) {
+	// crosses the dateline, e.g. a Pacific tile viewport
+	pacific := NewGeoBound(170, -170, -10, 10)
+
+	// on the +170 side of the dateline
+	near1 := NewBound(175, 179, -1, 1)
+	if !pacific.Intersects(near1) {
+		t.Error("expected wrapping bound to intersect a bound on its west side")
+	}
+
+	// on the -170 side of the dateline
+	near2 := NewBound(-179, -175, -1, 1)
+	if !pacific.Intersects(near2) {
+		t.Error("expected wrapping bound to intersect a bound on its east side")
+	}
+
+	// nowhere near the dateline
+	far := NewBound(0, 10, -1, 1)
+	if pacific.Intersects(far) {
+		t.Error("expected wrapping bound not to intersect a bound nowhere near the dateline")
+	}
+}
+
+func TestIntersectsLatitudeSeparated(t *testing.T) {
+	a := NewBound(-10, 10, -10, -5)
+	b := NewBound(-10, 10, 5, 10)
+
+	if a.Intersects(b) {
+		t.Error("expected latitude-separated bounds not to intersect")
+	}
+}
+
+func TestNewGeoBoundFromPathWrapsAcrossDateline(t *testing.T) {
+	path := NewPath()
+	path.Push(&Point{-170, 40})
+	path.Push(&Point{170, 40})
+
+	b := NewGeoBoundFromPath(path)
+
+	if !b.wraps {
+		t.Fatalf("expected a bound wrapping the antimeridian, got %v", b)
+	}
+
+	// the short (~20 degree) arc through the dateline, not the long
+	// (~340 degree) Cartesian envelope.
+	if b.Width() > 30 {
+		t.Errorf("expected a narrow wrapping span, got width %v (bound %v)", b.Width(), b)
+	}
+
+	for _, lng := range []float64{-180, -175, 175, 180} {
+		if !b.Contains(&Point{lng, 40}) {
+			t.Errorf("expected bound %v to contain (%v, 40) near the dateline", b, lng)
+		}
+	}
+
+	if b.Contains(&Point{0, 40}) {
+		t.Errorf("expected bound %v not to contain a point on the far side of the globe", b)
+	}
+}
+
+func TestNewGeoBoundFromPathMultiEdgeWrap(t *testing.T) {
+	path := NewPath()
+	path.Push(&Point{175, 10})
+	path.Push(&Point{-175, 12})
+	path.Push(&Point{-178, 8})
+
+	b := NewGeoBoundFromPath(path)
+
+	for _, p := range path.Points() {
+		if !b.Contains(p) {
+			t.Errorf("expected bound %v to contain path vertex %v", b, p)
+		}
+	}
+
+	if !b.wraps {
+		t.Errorf("expected a bound wrapping the antimeridian, got %v", b)
+	}
+}
@@ -0,0 +1,161 @@
+package geo
+
+import "math"
+
+// defaultBoundIndexPrecision is the geohash precision, in characters, used
+// by NewBoundIndex. 6 characters gives ~1.2km x 0.6km cells, a reasonable
+// default for neighborhood-sized polygons.
+const defaultBoundIndexPrecision = 6
+
+// BoundIndex is a simple spatial index of *Bound values, bucketed by
+// geohash cell at a fixed precision. It's meant as a cheap prefilter: given
+// a point or a bound, it returns the ids whose bound might overlap, so a
+// caller can then run an exact test (e.g. PolygonSet's ray cast) against a
+// much smaller candidate set instead of every indexed entry.
+type BoundIndex struct {
+	precision int
+	buckets   map[string][]int
+}
+
+// NewBoundIndex creates an empty BoundIndex at the default precision.
+func NewBoundIndex() *BoundIndex {
+	return NewBoundIndexWithPrecision(defaultBoundIndexPrecision)
+}
+
+// NewBoundIndexWithPrecision creates an empty BoundIndex bucketed by
+// geohash cells `precision` characters long. Lower precision means fewer,
+// bigger cells and cheaper inserts but coarser candidate sets.
+func NewBoundIndexWithPrecision(precision int) *BoundIndex {
+	if precision < 1 {
+		panic("invalid geohash precision")
+	}
+
+	return &BoundIndex{
+		precision: precision,
+		buckets:   make(map[string][]int),
+	}
+}
+
+// Insert adds id to every geohash cell b overlaps. A bound that crosses the
+// antimeridian is split first, so both halves are indexed correctly.
+func (idx *BoundIndex) Insert(id int, b *Bound) {
+	for _, part := range b.Split() {
+		for _, cell := range idx.cellsCovering(part) {
+			idx.buckets[cell] = append(idx.buckets[cell], id)
+		}
+	}
+}
+
+// Search returns the (deduplicated) ids whose bound may contain p. Since
+// Insert stores an id in every cell its bound overlaps, the cell containing
+// p already holds every candidate; false negatives aren't possible, only
+// false positives near a bound's edge.
+func (idx *BoundIndex) Search(p *Point) []int {
+	return dedupeIDs(idx.buckets[geohashEncode(p.Lat(), p.Lng(), idx.precision)])
+}
+
+// SearchBound returns the (deduplicated) ids whose bound may overlap b.
+func (idx *BoundIndex) SearchBound(b *Bound) []int {
+	var ids []int
+	for _, part := range b.Split() {
+		for _, cell := range idx.cellsCovering(part) {
+			ids = append(ids, idx.buckets[cell]...)
+		}
+	}
+
+	return dedupeIDs(ids)
+}
+
+// cellsCovering enumerates the geohash cells, at the index's precision,
+// that a non-wrapping bound overlaps.
+func (idx *BoundIndex) cellsCovering(b *Bound) []string {
+	lonBits, latBits := geohashBitsForPrecision(idx.precision)
+	lonStep := 360.0 / math.Exp2(float64(lonBits))
+	latStep := 180.0 / math.Exp2(float64(latBits))
+
+	lonLo := int(math.Floor((b.sw.X() + 180) / lonStep))
+	lonHi := int(math.Floor((b.ne.X() + 180) / lonStep))
+	latLo := int(math.Floor((b.sw.Y() + 90) / latStep))
+	latHi := int(math.Floor((b.ne.Y() + 90) / latStep))
+
+	cells := make([]string, 0, (lonHi-lonLo+1)*(latHi-latLo+1))
+	for i := lonLo; i <= lonHi; i++ {
+		lng := (float64(i)+0.5)*lonStep - 180
+		for j := latLo; j <= latHi; j++ {
+			lat := (float64(j)+0.5)*latStep - 90
+			cells = append(cells, geohashEncode(lat, lng, idx.precision))
+		}
+	}
+
+	return cells
+}
+
+// geohashBitsForPrecision returns how many of the precision*5 total bits go
+// to longitude vs. latitude; geohash interleaves starting with longitude.
+func geohashBitsForPrecision(precision int) (lonBits, latBits int) {
+	bits := precision * 5
+	lonBits = (bits + 1) / 2
+	latBits = bits / 2
+	return lonBits, latBits
+}
+
+// geohashEncode encodes a lat/lng pair into a geohash string of the given
+// precision, the inverse of geoHash2ranges.
+func geohashEncode(lat, lng float64, precision int) string {
+	latMin, latMax := -90.0, 90.0
+	lngMin, lngMax := -180.0, 180.0
+	even := true
+
+	buf := make([]byte, precision)
+	bit, ch := 0, 0
+	for i := 0; i < precision; {
+		if even {
+			mid := (lngMin + lngMax) / 2.0
+			if lng >= mid {
+				ch |= 0x10 >> uint(bit)
+				lngMin = mid
+			} else {
+				lngMax = mid
+			}
+		} else {
+			mid := (latMin + latMax) / 2.0
+			if lat >= mid {
+				ch |= 0x10 >> uint(bit)
+				latMin = mid
+			} else {
+				latMax = mid
+			}
+		}
+		even = !even
+
+		if bit < 4 {
+			bit++
+		} else {
+			buf[i] = geohashAlphabet[ch]
+			i++
+			bit, ch = 0, 0
+		}
+	}
+
+	return string(buf)
+}
+
+// dedupeIDs returns the unique ids in ids, without disturbing ids itself --
+// callers may pass a slice taken directly from a BoundIndex's internal
+// bucket, which must not be mutated in place.
+func dedupeIDs(ids []int) []int {
+	if len(ids) < 2 {
+		return ids
+	}
+
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,17 @@
+package geojson
+
+// Feature is a single GeoJSON Feature: a geometry plus arbitrary properties.
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection. Pass it to Unmarshal to
+// load a file of features and then call Decode on each Feature's Geometry
+// to get back typed *geo.Point / *geo.Path / *geo.PointSet / *geo.Bound
+// values.
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
@@ -0,0 +1,96 @@
+// Package geojson marshals and unmarshals the geo package's types to and
+// from GeoJSON (RFC 7946): *geo.Point as a Point, *geo.Path and
+// *geo.PointSet as a LineString/MultiPoint, and *geo.Bound as a Polygon
+// (or, if it crosses the antimeridian, a MultiPolygon of its two halves,
+// per the RFC 7946 antimeridian-cutting recommendation).
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	geo "github.com/strava/go.geo"
+)
+
+// Marshal encodes a *geo.Point, *geo.Path, *geo.PointSet or *geo.Bound as a
+// GeoJSON geometry.
+func Marshal(v interface{}) ([]byte, error) {
+	g, err := encodeGeometry(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(g)
+}
+
+// Unmarshal decodes GeoJSON into v, which must be a pointer to one of
+// *geo.Point, *geo.Path, *geo.PointSet, *geo.Bound or FeatureCollection,
+// e.g.
+//
+//	var p *geo.Point
+//	geojson.Unmarshal(data, &p)
+func Unmarshal(data []byte, v interface{}) error {
+	switch t := v.(type) {
+	case **geo.Point:
+		g, err := decodeGeometryBytes(data)
+		if err != nil {
+			return err
+		}
+		p, ok := g.(*geo.Point)
+		if !ok {
+			return fmt.Errorf("geojson: expected a Point geometry, got %s", geometryTypeName(g))
+		}
+		*t = p
+	case **geo.Path:
+		g, err := decodeGeometryBytes(data)
+		if err != nil {
+			return err
+		}
+		p, ok := g.(*geo.Path)
+		if !ok {
+			return fmt.Errorf("geojson: expected a LineString geometry, got %s", geometryTypeName(g))
+		}
+		*t = p
+	case **geo.PointSet:
+		g, err := decodeGeometryBytes(data)
+		if err != nil {
+			return err
+		}
+		p, ok := g.(*geo.PointSet)
+		if !ok {
+			return fmt.Errorf("geojson: expected a MultiPoint geometry, got %s", geometryTypeName(g))
+		}
+		*t = p
+	case **geo.Bound:
+		g, err := decodeGeometryBytes(data)
+		if err != nil {
+			return err
+		}
+		b, ok := g.(*geo.Bound)
+		if !ok {
+			return fmt.Errorf("geojson: expected a Polygon/MultiPolygon geometry, got %s", geometryTypeName(g))
+		}
+		*t = b
+	case *FeatureCollection:
+		return json.Unmarshal(data, t)
+	default:
+		return fmt.Errorf("geojson: unsupported unmarshal target %T", v)
+	}
+
+	return nil
+}
+
+func geometryTypeName(v interface{}) string {
+	switch v.(type) {
+	case *geo.Point:
+		return "Point"
+	case *geo.Path:
+		return "LineString"
+	case *geo.PointSet:
+		return "MultiPoint"
+	case *geo.Bound:
+		return "Polygon/MultiPolygon"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
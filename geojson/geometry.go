@@ -0,0 +1,217 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	geo "github.com/strava/go.geo"
+)
+
+// Geometry is a raw GeoJSON geometry object. Coordinates are kept as
+// json.RawMessage so they can be decoded into the concrete shape the type
+// field says to expect, rather than the generic []interface{} soup
+// encoding/json would otherwise produce.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates,omitempty"`
+	BBox        []float64       `json:"bbox,omitempty"`
+}
+
+// Decode converts the geometry into the matching geo type: *geo.Point,
+// *geo.Path, *geo.PointSet or *geo.Bound.
+func (g *Geometry) Decode() (interface{}, error) {
+	switch g.Type {
+	case "Point":
+		var coords [2]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, fmt.Errorf("geojson: invalid Point coordinates: %v", err)
+		}
+		return &geo.Point{coords[0], coords[1]}, nil
+	case "LineString":
+		coords, err := decodePositions(g.Coordinates)
+		if err != nil {
+			return nil, fmt.Errorf("geojson: invalid LineString coordinates: %v", err)
+		}
+		path := geo.NewPath()
+		for _, c := range coords {
+			path.Push(&geo.Point{c[0], c[1]})
+		}
+		return path, nil
+	case "MultiPoint":
+		coords, err := decodePositions(g.Coordinates)
+		if err != nil {
+			return nil, fmt.Errorf("geojson: invalid MultiPoint coordinates: %v", err)
+		}
+		ps := geo.NewPointSet()
+		for _, c := range coords {
+			ps.Push(&geo.Point{c[0], c[1]})
+		}
+		return ps, nil
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("geojson: invalid Polygon coordinates: %v", err)
+		}
+		return boundFromRing(rings)
+	case "MultiPolygon":
+		var polys [][][][2]float64
+		if err := json.Unmarshal(g.Coordinates, &polys); err != nil {
+			return nil, fmt.Errorf("geojson: invalid MultiPolygon coordinates: %v", err)
+		}
+		return boundFromAntimeridianSplitPolygons(polys)
+	default:
+		return nil, fmt.Errorf("geojson: unsupported geometry type %q", g.Type)
+	}
+}
+
+func decodePositions(raw json.RawMessage) ([][2]float64, error) {
+	var coords [][2]float64
+	err := json.Unmarshal(raw, &coords)
+	return coords, err
+}
+
+// boundFromRing rebuilds a *geo.Bound from the 5-vertex axis-aligned
+// rectangle ring this package emits for Marshal, in the sw, nw, ne, se, sw
+// vertex order ringFor uses. Rings that don't describe that exact shape
+// aren't supported since *geo.Bound can't represent them, and are rejected
+// rather than silently collapsed to their min/max envelope.
+func boundFromRing(rings [][][2]float64) (*geo.Bound, error) {
+	if len(rings) != 1 || len(rings[0]) != 5 {
+		return nil, fmt.Errorf("geojson: only single-ring, axis-aligned rectangle polygons can decode to a Bound")
+	}
+
+	ring := rings[0]
+	sw, nw, ne, se, closing := ring[0], ring[1], ring[2], ring[3], ring[4]
+
+	if closing != sw {
+		return nil, fmt.Errorf("geojson: only single-ring, axis-aligned rectangle polygons can decode to a Bound")
+	}
+
+	if sw[0] != nw[0] || ne[0] != se[0] || nw[1] != ne[1] || sw[1] != se[1] {
+		return nil, fmt.Errorf("geojson: only single-ring, axis-aligned rectangle polygons can decode to a Bound")
+	}
+
+	return geo.NewBound(sw[0], ne[0], sw[1], ne[1]), nil
+}
+
+// boundFromAntimeridianSplitPolygons reconstructs the original wrapping
+// *geo.Bound from the two rectangles emitted for a dateline-crossing bound:
+// one hugging the west edge at +180 and one hugging the east edge at -180.
+func boundFromAntimeridianSplitPolygons(polys [][][][2]float64) (*geo.Bound, error) {
+	if len(polys) != 2 {
+		return nil, fmt.Errorf("geojson: only a two-polygon, antimeridian-split MultiPolygon can decode to a Bound")
+	}
+
+	bounds := make([]*geo.Bound, 0, 2)
+	for _, poly := range polys {
+		b, err := boundFromRing(poly)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, b)
+	}
+
+	// the half hugging +180 carries the original west edge; the half
+	// hugging -180 carries the original east edge.
+	var westHalf, eastHalf *geo.Bound
+	for _, b := range bounds {
+		switch {
+		case b.NorthEast().Lng() == 180:
+			westHalf = b
+		case b.SouthWest().Lng() == -180:
+			eastHalf = b
+		}
+	}
+
+	if westHalf == nil || eastHalf == nil {
+		return nil, fmt.Errorf("geojson: MultiPolygon doesn't look like an antimeridian split (expected one half touching +180 and one touching -180)")
+	}
+
+	return geo.NewGeoBound(
+		westHalf.SouthWest().Lng(),
+		eastHalf.NorthEast().Lng(),
+		westHalf.SouthWest().Lat(),
+		westHalf.NorthEast().Lat(),
+	), nil
+}
+
+func encodeGeometry(v interface{}) (*Geometry, error) {
+	switch t := v.(type) {
+	case *geo.Point:
+		coords, err := json.Marshal([2]float64{t.Lng(), t.Lat()})
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "Point", Coordinates: coords}, nil
+	case *geo.Path:
+		coords, err := json.Marshal(positionsFor(t.Points()))
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "LineString", Coordinates: coords}, nil
+	case *geo.PointSet:
+		coords, err := json.Marshal(positionsFor(t.Points()))
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "MultiPoint", Coordinates: coords}, nil
+	case *geo.Bound:
+		return encodeBound(t)
+	default:
+		return nil, fmt.Errorf("geojson: unsupported type %T", v)
+	}
+}
+
+func positionsFor(points []*geo.Point) [][2]float64 {
+	coords := make([][2]float64, len(points))
+	for i, p := range points {
+		coords[i] = [2]float64{p.Lng(), p.Lat()}
+	}
+	return coords
+}
+
+func encodeBound(b *geo.Bound) (*Geometry, error) {
+	parts := b.Split()
+	bbox := []float64{b.SouthWest().Lng(), b.SouthWest().Lat(), b.NorthEast().Lng(), b.NorthEast().Lat()}
+
+	if len(parts) == 1 {
+		coords, err := json.Marshal([][][2]float64{ringFor(parts[0])})
+		if err != nil {
+			return nil, err
+		}
+		return &Geometry{Type: "Polygon", Coordinates: coords, BBox: bbox}, nil
+	}
+
+	polys := make([][][][2]float64, len(parts))
+	for i, part := range parts {
+		polys[i] = [][][2]float64{ringFor(part)}
+	}
+
+	coords, err := json.Marshal(polys)
+	if err != nil {
+		return nil, err
+	}
+	return &Geometry{Type: "MultiPolygon", Coordinates: coords, BBox: bbox}, nil
+}
+
+// ringFor returns the closed, 5-vertex rectangle ring for a (non-wrapping)
+// bound, in the same vertex order bound.go's ToMysqlPolygon uses.
+func ringFor(b *geo.Bound) [][2]float64 {
+	sw, ne := b.SouthWest(), b.NorthEast()
+	return [][2]float64{
+		{sw.Lng(), sw.Lat()},
+		{sw.Lng(), ne.Lat()},
+		{ne.Lng(), ne.Lat()},
+		{ne.Lng(), sw.Lat()},
+		{sw.Lng(), sw.Lat()},
+	}
+}
+
+func decodeGeometryBytes(data []byte) (interface{}, error) {
+	var g Geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+
+	return g.Decode()
+}
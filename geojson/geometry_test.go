@@ -0,0 +1,181 @@
+package geojson
+
+import (
+	"encoding/json"
+	"testing"
+
+	geo "github.com/strava/go.geo"
+)
+
+func TestBoundRoundTrip(t *testing.T) {
+	b := geo.NewBound(-10, 10, -5, 5)
+
+	data, err := Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded *geo.Bound
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !decoded.Equals(b) {
+		t.Errorf("round-tripped bound = %v, want %v", decoded, b)
+	}
+}
+
+func TestBoundFromRingRejectsNonRectangle(t *testing.T) {
+	g := &Geometry{
+		Type:        "Polygon",
+		Coordinates: []byte(`[[[0,0],[10,10],[20,0],[0,0]]]`),
+	}
+
+	if _, err := g.Decode(); err == nil {
+		t.Error("expected a non-rectangle ring to be rejected, got nil error")
+	}
+}
+
+func TestBoundFromRingRejectsWrongVertexCount(t *testing.T) {
+	g := &Geometry{
+		Type:        "Polygon",
+		Coordinates: []byte(`[[[0,0],[0,10],[10,10],[10,0]]]`),
+	}
+
+	if _, err := g.Decode(); err == nil {
+		t.Error("expected a 4-vertex (unclosed) ring to be rejected, got nil error")
+	}
+}
+
+func TestWrappingBoundRoundTrip(t *testing.T) {
+	b := geo.NewGeoBound(170, -170, -10, 10)
+
+	data, err := Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var g Geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		t.Fatalf("unmarshal geometry: %v", err)
+	}
+	if g.Type != "MultiPolygon" {
+		t.Fatalf("expected a wrapping bound to marshal as a MultiPolygon, got %q", g.Type)
+	}
+
+	var decoded *geo.Bound
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !decoded.Equals(b) {
+		t.Errorf("round-tripped wrapping bound = %v, want %v", decoded, b)
+	}
+}
+
+func TestPointRoundTrip(t *testing.T) {
+	p := &geo.Point{12.3, -45.6}
+
+	data, err := Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded *geo.Point
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !decoded.Equals(p) {
+		t.Errorf("round-tripped point = %v, want %v", decoded, p)
+	}
+}
+
+func TestPathRoundTrip(t *testing.T) {
+	path := geo.NewPath()
+	path.Push(&geo.Point{0, 0})
+	path.Push(&geo.Point{1, 1})
+	path.Push(&geo.Point{2, -1})
+
+	data, err := Marshal(path)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded *geo.Path
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	points := decoded.Points()
+	if len(points) != len(path.Points()) {
+		t.Fatalf("round-tripped path has %d points, want %d", len(points), len(path.Points()))
+	}
+	for i, p := range path.Points() {
+		if !points[i].Equals(p) {
+			t.Errorf("point %d = %v, want %v", i, points[i], p)
+		}
+	}
+}
+
+func TestPointSetRoundTrip(t *testing.T) {
+	ps := geo.NewPointSet()
+	ps.Push(&geo.Point{0, 0})
+	ps.Push(&geo.Point{5, -5})
+
+	data, err := Marshal(ps)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded *geo.PointSet
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	points := decoded.Points()
+	if len(points) != len(ps.Points()) {
+		t.Fatalf("round-tripped point set has %d points, want %d", len(points), len(ps.Points()))
+	}
+	for i, p := range ps.Points() {
+		if !points[i].Equals(p) {
+			t.Errorf("point %d = %v, want %v", i, points[i], p)
+		}
+	}
+}
+
+func TestFeatureCollectionDecode(t *testing.T) {
+	data := []byte(`{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"name": "a"}, "geometry": {"type": "Point", "coordinates": [1, 2]}},
+			{"type": "Feature", "properties": {"name": "b"}, "geometry": {"type": "LineString", "coordinates": [[0,0],[1,1]]}}
+		]
+	}`)
+
+	var fc FeatureCollection
+	if err := Unmarshal(data, &fc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(fc.Features) != 2 {
+		t.Fatalf("expected 2 features, got %d", len(fc.Features))
+	}
+
+	p, err := fc.Features[0].Geometry.Decode()
+	if err != nil {
+		t.Fatalf("decode feature 0 geometry: %v", err)
+	}
+	point, ok := p.(*geo.Point)
+	if !ok || point.Lng() != 1 || point.Lat() != 2 {
+		t.Errorf("feature 0 decoded to %v, want Point(1, 2)", p)
+	}
+
+	l, err := fc.Features[1].Geometry.Decode()
+	if err != nil {
+		t.Fatalf("decode feature 1 geometry: %v", err)
+	}
+	if _, ok := l.(*geo.Path); !ok {
+		t.Errorf("feature 1 decoded to %T, want *geo.Path", l)
+	}
+}
@@ -0,0 +1,167 @@
+package index
+
+import (
+	"bytes"
+	"sort"
+
+	geo "github.com/strava/go.geo"
+)
+
+// maxDepth bounds the recursion in PrefixTermRanges: after 40 halvings (20
+// per axis, cells of roughly 0.0003 degrees) a cell is emitted whole rather
+// than split further, which is coarser than EncodePoint's 64-bit full
+// precision but keeps a degenerate (zero-area) bound from recursing without
+// end.
+const maxDepth = 40
+
+// PrefixTermRanges decomposes b into the minimal set of [min, max]
+// prefix-coded term ranges, at the given precision step, that together
+// cover every cell intersecting b. A caller drives an external inverted
+// index with these ranges to find candidate points without scanning the
+// whole data set; because the ranges are cell-aligned they are a superset
+// of the true bound, so results still need a FilteringPointSet pass.
+func PrefixTermRanges(b *geo.Bound, step uint) []TermRange {
+	if step == 0 {
+		step = DefaultStep
+	}
+
+	var ranges []TermRange
+	for _, part := range b.Split() {
+		collectTermRanges(part, step, &ranges)
+	}
+
+	return mergeRanges(ranges)
+}
+
+func collectTermRanges(b *geo.Bound, step uint, ranges *[]TermRange) {
+	var descend func(prefix uint64, bits uint, lonMin, lonMax, latMin, latMax float64)
+	descend = func(prefix uint64, bits uint, lonMin, lonMax, latMin, latMax float64) {
+		cell := geo.NewBound(lonMin, lonMax, latMin, latMax)
+		if !b.Intersects(cell) {
+			return
+		}
+
+		if bits >= maxDepth || cellFullyInside(b, lonMin, lonMax, latMin, latMax) {
+			*ranges = append(*ranges, termRangeForCell(prefix, bits, step))
+			return
+		}
+
+		// even depths split latitude, odd depths split longitude: interleave
+		// puts latitude in the most significant bit of each pair, and prefix
+		// bits are laid down from the top of the code.
+		if bits%2 == 0 {
+			mid := (latMin + latMax) / 2
+			descend(prefix<<1, bits+1, lonMin, lonMax, latMin, mid)
+			descend(prefix<<1|1, bits+1, lonMin, lonMax, mid, latMax)
+		} else {
+			mid := (lonMin + lonMax) / 2
+			descend(prefix<<1, bits+1, lonMin, mid, latMin, latMax)
+			descend(prefix<<1|1, bits+1, mid, lonMax, latMin, latMax)
+		}
+	}
+
+	descend(0, 0, -180, 180, -90, 90)
+}
+
+func cellFullyInside(b *geo.Bound, lonMin, lonMax, latMin, latMax float64) bool {
+	corners := [4]*geo.Point{
+		{lonMin, latMin}, {lonMin, latMax}, {lonMax, latMin}, {lonMax, latMax},
+	}
+
+	for _, c := range corners {
+		if !b.Contains(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// termRangeForCell builds the inclusive term range covering every code in a
+// cell, rounded out to the nearest step boundary so it lines up with the
+// precision terms EncodePoint actually produces.
+func termRangeForCell(prefix uint64, bits, step uint) TermRange {
+	shift := uint(64)
+	if bits < 64 {
+		shift = 64 - bits
+	}
+
+	base := prefix << shift
+
+	var mask uint64
+	if shift >= 64 {
+		mask = ^uint64(0)
+	} else {
+		mask = uint64(1)<<shift - 1
+	}
+
+	alignedShift := ((shift + step - 1) / step) * step
+	if max := maxTermShift(step); alignedShift > max {
+		alignedShift = max
+	}
+
+	return TermRange{
+		Min: encodeTerm(base, alignedShift),
+		Max: encodeTerm(base|mask, alignedShift),
+	}
+}
+
+// maxTermShift returns the coarsest shift EncodePoint actually emits for a
+// given step: the largest multiple of step that is still < 64. Rounding a
+// cell's shift up to 64 itself would produce a term no stored EncodePoint
+// term -- which always has shift < 64 -- could ever match.
+func maxTermShift(step uint) uint {
+	return ((64 - 1) / step) * step
+}
+
+// mergeRanges sorts and coalesces overlapping or touching ranges so callers
+// don't issue redundant scans of the same index region.
+func mergeRanges(ranges []TermRange) []TermRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i].Min, ranges[j].Min) < 0
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if bytes.Compare(r.Min, last.Max) <= 0 {
+			if bytes.Compare(r.Max, last.Max) > 0 {
+				last.Max = r.Max
+			}
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+// FilteringPointSet wraps a bound and weeds out the false positives that
+// the coarse prefix cells from PrefixTermRanges let through, by running the
+// same exact Bound.Contains check the rest of this package uses.
+type FilteringPointSet struct {
+	bound *geo.Bound
+}
+
+// NewFilteringPointSet creates a FilteringPointSet for the given bound.
+func NewFilteringPointSet(b *geo.Bound) *FilteringPointSet {
+	return &FilteringPointSet{bound: b}
+}
+
+// Filter returns the subset of points that are actually contained by the
+// bound, in place, reusing points' backing array.
+func (f *FilteringPointSet) Filter(points []*geo.Point) []*geo.Point {
+	kept := points[:0]
+	for _, p := range points {
+		if f.bound.Contains(p) {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,37 @@
+package index
+
+import (
+	"bytes"
+	"testing"
+
+	geo "github.com/strava/go.geo"
+)
+
+func TestPrefixTermRangesWholeGlobe(t *testing.T) {
+	globe := geo.NewBound(-180, 180, -90, 90)
+	ranges := PrefixTermRanges(globe, DefaultStep)
+
+	p := &geo.Point{12.3, 45.6}
+	terms := EncodePoint(p, DefaultStep)
+
+	matched := false
+	for _, term := range terms {
+		for _, r := range ranges {
+			if bytes.Compare(term, r.Min) >= 0 && bytes.Compare(term, r.Max) <= 0 {
+				matched = true
+			}
+		}
+	}
+
+	if !matched {
+		t.Error("a whole-globe query should produce a term range matching any point's stored terms")
+	}
+}
+
+func TestMaxTermShiftBelow64(t *testing.T) {
+	for _, step := range []uint{1, 9, 16, 32} {
+		if max := maxTermShift(step); max >= 64 {
+			t.Errorf("maxTermShift(%d) = %d, want < 64 since EncodePoint never emits shift 64", step, max)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package index
+
+import (
+	"encoding/binary"
+	"math"
+
+	geo "github.com/strava/go.geo"
+)
+
+const (
+	lngScale = float64(1<<32) / 360.0
+	latScale = float64(1<<32) / 180.0
+)
+
+// EncodePoint returns the prefix-coded terms for p, from full precision
+// (64 bits, shift 0) down to the largest multiple of step below 64, trimmed
+// by step bits at a time. step defaults
+// to DefaultStep when not given. The terms are ordered from most to least
+// precise and are suitable for insertion into an inverted index: a query
+// can then pick whichever terms match the precision it wants to search at.
+func EncodePoint(p *geo.Point, step ...uint) [][]byte {
+	s := resolveStep(step)
+	code := interleave(scaleLng(p.Lng()), scaleLat(p.Lat()))
+
+	terms := make([][]byte, 0, 64/s+1)
+	for shift := uint(0); shift < 64; shift += s {
+		terms = append(terms, encodeTerm(code, shift))
+	}
+
+	return terms
+}
+
+// DecodePoint reverses a full-precision (shift 0) term produced by
+// EncodePoint back into a *geo.Point. Decoding a coarser, lower-precision
+// term returns the point at the southwest corner of the cell it represents.
+func DecodePoint(term []byte) *geo.Point {
+	code, shift := decodeTerm(term)
+	code &^= (uint64(1) << shift) - 1
+
+	lng, lat := deinterleave(code)
+	return &geo.Point{unscaleLng(lng), unscaleLat(lat)}
+}
+
+func resolveStep(step []uint) uint {
+	if len(step) > 0 && step[0] > 0 {
+		return step[0]
+	}
+
+	return DefaultStep
+}
+
+// encodeTerm packs the shift, as a single leading byte, and the value of
+// code with its low `shift` bits cleared into a fixed-width, byte-lexically
+// ordered term.
+func encodeTerm(code uint64, shift uint) []byte {
+	term := make([]byte, 9)
+	term[0] = byte(shift)
+	binary.BigEndian.PutUint64(term[1:], code&^((uint64(1)<<shift)-1))
+
+	return term
+}
+
+func decodeTerm(term []byte) (code uint64, shift uint) {
+	shift = uint(term[0])
+	code = binary.BigEndian.Uint64(term[1:])
+
+	return code, shift
+}
+
+// scaleLng and scaleLat clamp to math.MaxUint32 before the uint32 cast:
+// at the edge values lng=180 or lat=90, (lng+180.0)*lngScale equals exactly
+// 1<<32, which silently wraps to 0 on conversion and aliases the point to
+// the opposite edge of the scaled range.
+func scaleLng(lng float64) uint32 {
+	v := (lng + 180.0) * lngScale
+	if v >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(v)
+}
+
+func scaleLat(lat float64) uint32 {
+	v := (lat + 90.0) * latScale
+	if v >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(v)
+}
+
+func unscaleLng(v uint32) float64 {
+	return float64(v)/lngScale - 180.0
+}
+
+func unscaleLat(v uint32) float64 {
+	return float64(v)/latScale - 90.0
+}
+
+// interleave bit-interleaves lon and lat into a 64 bit Morton (Z-order)
+// code, lon occupying the even bits and lat the odd bits. This gives the
+// property that any square, power-of-two-aligned cell corresponds to a
+// single contiguous range of codes, which is what makes PrefixTermRanges
+// possible.
+func interleave(lon, lat uint32) uint64 {
+	return spreadBits(lon) | (spreadBits(lat) << 1)
+}
+
+func deinterleave(code uint64) (lon, lat uint32) {
+	return squashBits(code), squashBits(code >> 1)
+}
+
+func spreadBits(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | (x << 16)) & 0x0000ffff0000ffff
+	x = (x | (x << 8)) & 0x00ff00ff00ff00ff
+	x = (x | (x << 4)) & 0x0f0f0f0f0f0f0f0f
+	x = (x | (x << 2)) & 0x3333333333333333
+	x = (x | (x << 1)) & 0x5555555555555555
+	return x
+}
+
+func squashBits(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | (x >> 1)) & 0x3333333333333333
+	x = (x | (x >> 2)) & 0x0f0f0f0f0f0f0f0f
+	x = (x | (x >> 4)) & 0x00ff00ff00ff00ff
+	x = (x | (x >> 8)) & 0x0000ffff0000ffff
+	x = (x | (x >> 16)) & 0x00000000ffffffff
+	return uint32(x)
+}
@@ -0,0 +1,46 @@
+package index
+
+import (
+	"testing"
+
+	geo "github.com/strava/go.geo"
+)
+
+func TestScaleEdgeValues(t *testing.T) {
+	if scaleLng(180) <= scaleLng(179.9999) {
+		t.Error("scaleLng(180) should scale above interior values, not wrap to 0")
+	}
+	if scaleLng(180) == scaleLng(-180) {
+		t.Error("scaleLng(180) and scaleLng(-180) must not collide")
+	}
+
+	if scaleLat(90) <= scaleLat(89.9999) {
+		t.Error("scaleLat(90) should scale above interior values, not wrap to 0")
+	}
+	if scaleLat(90) == scaleLat(-90) {
+		t.Error("scaleLat(90) and scaleLat(-90) must not collide")
+	}
+}
+
+func TestEncodeDecodePointRoundTrip(t *testing.T) {
+	cases := []*geo.Point{
+		{12.3, 45.6},
+		{-179.999, -89.999},
+		{180, 90},
+		{-180, -90},
+		{0, 0},
+	}
+
+	for _, p := range cases {
+		terms := EncodePoint(p)
+		decoded := DecodePoint(terms[0])
+
+		const tolerance = 1e-4
+		if diff := decoded.Lng() - p.Lng(); diff > tolerance || diff < -tolerance {
+			t.Errorf("EncodePoint/DecodePoint(%v): lng got %v", p, decoded.Lng())
+		}
+		if diff := decoded.Lat() - p.Lat(); diff > tolerance || diff < -tolerance {
+			t.Errorf("EncodePoint/DecodePoint(%v): lat got %v", p, decoded.Lat())
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Package index provides the building blocks for driving an external
+// inverted index (Bleve, Bolt, Badger, RocksDB, ...) with geo.Point and
+// geo.Bound values, mirroring the approach Lucene and Bleve use for
+// numeric/geo fields: each point is encoded as a set of prefix-coded terms
+// at decreasing precision, and a bounding box query is answered by asking
+// the index for the union of a handful of term ranges rather than scanning
+// every point.
+package index
+
+// DefaultStep is the number of bits trimmed between successive precision
+// terms when none is specified, matching Lucene's default precisionStep.
+const DefaultStep = 9
+
+// TermRange is an inclusive [Min, Max] range of prefix-coded terms. Both
+// bounds are comparable byte-lexicographically, so any index backend that
+// supports a byte-range scan can use a TermRange directly.
+type TermRange struct {
+	Min, Max []byte
+}
@@ -0,0 +1,194 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// PolygonSet is a collection of polygons, each a closed ring of points,
+// backed by a BoundIndex so that point-in-polygon queries only run the
+// exact ray-cast test against polygons whose bound could plausibly contain
+// the point. This mirrors a typical bounding-box-prefilter-then-exact-test
+// workflow, and is meant for testing many points against many polygons,
+// e.g. a batch of GPS points against a set of neighborhood boundaries.
+type PolygonSet struct {
+	index      *BoundIndex
+	ids        []int
+	rings      []*Path
+	bounds     []*Bound
+	idToOffset map[int]int
+}
+
+// NewPolygonSet creates an empty PolygonSet.
+func NewPolygonSet() *PolygonSet {
+	return &PolygonSet{
+		index:      NewBoundIndex(),
+		idToOffset: make(map[int]int),
+	}
+}
+
+// Add registers a polygon, identified by id, as the closed ring of points
+// in ring. The ring does not need to repeat its first point as its last;
+// the ray-cast test below closes it implicitly.
+func (ps *PolygonSet) Add(id int, ring *Path) *PolygonSet {
+	points := ring.Points()
+	if len(points) < 3 {
+		panic("a polygon ring needs at least 3 points")
+	}
+
+	b := ringBound(points)
+
+	ps.idToOffset[id] = len(ps.ids)
+	ps.ids = append(ps.ids, id)
+	ps.rings = append(ps.rings, ring)
+	ps.bounds = append(ps.bounds, b)
+	ps.index.Insert(id, b)
+
+	return ps
+}
+
+// PointInPolygon returns the id of a polygon containing p, and true, or
+// (0, false) if none contains it. If an optional within (meters) is given
+// and no polygon contains p outright, polygons whose bound comes within
+// that many meters of p -- found by widening the index search the same way
+// NewBoundAroundPoint does -- are accepted if any of their ring's vertices
+// is within that distance, reusing GeoPad the way GeoDistanceFrom queries
+// elsewhere in this package do.
+func (ps *PolygonSet) PointInPolygon(p *Point, within ...float64) (int, bool) {
+	for _, id := range ps.index.Search(p) {
+		offset := ps.idToOffset[id]
+		if ps.bounds[offset].Contains(p) && ringContains(ps.rings[offset], p, ps.bounds[offset].wraps) {
+			return id, true
+		}
+	}
+
+	if len(within) == 0 || within[0] <= 0 {
+		return 0, false
+	}
+
+	meters := within[0]
+	search := NewBoundAroundPoint(p, meters)
+	for _, id := range ps.index.SearchBound(search) {
+		offset := ps.idToOffset[id]
+		if ps.bounds[offset].Clone().GeoPad(meters).Contains(p) && ringWithin(ps.rings[offset], p, meters) {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// PointsInPolygons is the batch form of PointInPolygon: for each point it
+// returns the containing polygon's id, or -1 if none contains it.
+func (ps *PolygonSet) PointsInPolygons(points []*Point, within ...float64) []int {
+	ids := make([]int, len(points))
+	for i, p := range points {
+		if id, ok := ps.PointInPolygon(p, within...); ok {
+			ids[i] = id
+		} else {
+			ids[i] = -1
+		}
+	}
+
+	return ids
+}
+
+// ringContains runs the standard even-odd ray-cast point-in-polygon test.
+// wraps should be the antimeridian-crossing ring's own bound.wraps: when
+// true, every longitude (ring vertices and p alike) is unwrapped into the
+// [0, 360) range first, since the ray cast otherwise breaks down across the
+// -180/180 seam.
+func ringContains(ring *Path, p *Point, wraps bool) bool {
+	points := ring.Points()
+	n := len(points)
+	inside := false
+
+	pLng := p.Lng()
+	if wraps && pLng < 0 {
+		pLng += 360
+	}
+
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		pi, pj := points[i], points[j]
+		piLng, pjLng := pi.Lng(), pj.Lng()
+		if wraps {
+			if piLng < 0 {
+				piLng += 360
+			}
+			if pjLng < 0 {
+				pjLng += 360
+			}
+		}
+
+		if (pi.Lat() > p.Lat()) != (pj.Lat() > p.Lat()) {
+			x := (pjLng-piLng)*(p.Lat()-pi.Lat())/(pj.Lat()-pi.Lat()) + piLng
+			if pLng < x {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// antimeridianGapThreshold bounds ringBound's antimeridian-crossing
+// heuristic to rings whose longitudes actually reach close to both edges
+// of the +/-180 seam. Without this, an ordinary ring whose vertices simply
+// leave a wide gap somewhere in the middle -- e.g. a sparse, near-global
+// ring that never comes near the dateline -- could be misclassified as
+// wrapping purely because that interior gap happens to be wider than the
+// wraparound one.
+const antimeridianGapThreshold = 150.0
+
+// ringBound returns the tight bound of a ring's points, detecting an
+// antimeridian crossing the same way Split()'s callers expect: if the
+// ring's longitudes reach both +antimeridianGapThreshold and
+// -antimeridianGapThreshold (i.e. come within 30 degrees of each side of
+// the seam), and the largest gap between its sorted longitudes is interior
+// rather than the wraparound gap from the max back to the min, the ring is
+// assumed to cross the dateline and its tight bound wraps around it.
+//
+// This is still a heuristic, not a true crossing test: it's based on the
+// distribution of vertex longitudes, not on the ring's actual winding, so
+// a ring that happens to place vertices near both edges of the seam
+// without truly crossing it (or vice versa) can still be misclassified.
+func ringBound(points []*Point) *Bound {
+	lngs := make([]float64, len(points))
+	south, north := points[0].Lat(), points[0].Lat()
+	for i, p := range points {
+		lngs[i] = p.Lng()
+		south = math.Min(south, p.Lat())
+		north = math.Max(north, p.Lat())
+	}
+	sort.Float64s(lngs)
+
+	gapIndex := -1
+	if lngs[0] <= -antimeridianGapThreshold && lngs[len(lngs)-1] >= antimeridianGapThreshold {
+		maxGap := 360 - (lngs[len(lngs)-1] - lngs[0])
+		for i := 0; i < len(lngs)-1; i++ {
+			if gap := lngs[i+1] - lngs[i]; gap > maxGap {
+				maxGap = gap
+				gapIndex = i
+			}
+		}
+	}
+
+	if gapIndex < 0 {
+		return NewBound(lngs[0], lngs[len(lngs)-1], south, north)
+	}
+
+	return NewGeoBound(lngs[gapIndex+1], lngs[gapIndex], south, north)
+}
+
+// ringWithin reports whether any vertex of ring is within meters of p. It's
+// an approximation of distance-to-polygon-boundary, adequate for "close to
+// the edge" queries without computing exact point-to-segment distances.
+func ringWithin(ring *Path, p *Point, meters float64) bool {
+	for _, v := range ring.Points() {
+		if p.GeoDistanceFrom(v, true) <= meters {
+			return true
+		}
+	}
+
+	return false
+}
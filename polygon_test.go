@@ -0,0 +1,70 @@
+package geo
+
+import "testing"
+
+func TestPolygonSetAntimeridian(t *testing.T) {
+	// a small rectangle straddling the dateline, like the western Aleutians
+	ring := NewPath()
+	ring.Push(&Point{179, -1})
+	ring.Push(&Point{179, 1})
+	ring.Push(&Point{-179, 1})
+	ring.Push(&Point{-179, -1})
+
+	ps := NewPolygonSet()
+	ps.Add(1, ring)
+
+	id, ok := ps.PointInPolygon(&Point{179.5, 0})
+	if !ok || id != 1 {
+		t.Errorf("expected point just west of the dateline to be inside, got (%d, %v)", id, ok)
+	}
+
+	id, ok = ps.PointInPolygon(&Point{-179.5, 0})
+	if !ok || id != 1 {
+		t.Errorf("expected point just east of the dateline to be inside, got (%d, %v)", id, ok)
+	}
+
+	_, ok = ps.PointInPolygon(&Point{0, 0})
+	if ok {
+		t.Error("expected a point on the far side of the globe to be outside")
+	}
+}
+
+func TestPolygonSetOrdinary(t *testing.T) {
+	ring := NewPath()
+	ring.Push(&Point{0, 0})
+	ring.Push(&Point{0, 1})
+	ring.Push(&Point{1, 1})
+	ring.Push(&Point{1, 0})
+
+	ps := NewPolygonSet()
+	ps.Add(1, ring)
+
+	if id, ok := ps.PointInPolygon(&Point{0.5, 0.5}); !ok || id != 1 {
+		t.Errorf("expected point inside ordinary ring, got (%d, %v)", id, ok)
+	}
+
+	if _, ok := ps.PointInPolygon(&Point{50, 50}); ok {
+		t.Error("expected point outside ordinary ring to be excluded")
+	}
+}
+
+func TestRingBoundDatelineCrossing(t *testing.T) {
+	points := []*Point{{179, -1}, {179, 1}, {-179, 1}, {-179, -1}}
+
+	b := ringBound(points)
+	if !b.wraps {
+		t.Errorf("expected a dateline-crossing ring to produce a wrapping bound, got %v", b)
+	}
+}
+
+func TestRingBoundSparseRingNearGlobeDoesNotFalsePositive(t *testing.T) {
+	// nowhere near the dateline, but its one interior gap (-85 to 92) is
+	// wider than the wraparound gap -- the false positive the antimeridian
+	// heuristic must not fall for.
+	points := []*Point{{-90, 0}, {-85, 10}, {92, 10}, {100, 0}}
+
+	b := ringBound(points)
+	if b.wraps {
+		t.Errorf("expected a ring nowhere near the dateline not to be misclassified as wrapping, got %v", b)
+	}
+}